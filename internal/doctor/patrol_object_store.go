@@ -0,0 +1,108 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/vcs"
+)
+
+// defaultObjectStoreRoot is where gastown keeps shared bare clones.
+func defaultObjectStoreRoot() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gastown", "objects")
+}
+
+// PatrolObjectStoreCheck flags orphaned worktrees in the shared
+// ObjectStore: entries the bare repo still tracks whose directories no
+// longer exist on disk.
+type PatrolObjectStoreCheck struct {
+	storeRoot     string
+	orphanedCount int
+}
+
+// NewPatrolObjectStoreCheck creates the object store health check.
+func NewPatrolObjectStoreCheck() *PatrolObjectStoreCheck {
+	return &PatrolObjectStoreCheck{storeRoot: defaultObjectStoreRoot()}
+}
+
+// Name returns the check's identifier.
+func (c *PatrolObjectStoreCheck) Name() string {
+	return "patrol-object-store"
+}
+
+// CanFix reports that this check can prune what it finds.
+func (c *PatrolObjectStoreCheck) CanFix() bool {
+	return true
+}
+
+// Run counts worktree entries across the shared store whose directories
+// are missing on disk. A town that has never migrated a rig into the
+// shared store (no store root yet) is not a warning.
+func (c *PatrolObjectStoreCheck) Run(ctx *CheckContext) Result {
+	c.orphanedCount = 0
+
+	if c.storeRoot == "" {
+		return Result{Status: StatusOK}
+	}
+	if _, err := os.Stat(c.storeRoot); os.IsNotExist(err) {
+		return Result{Status: StatusOK}
+	}
+
+	orphaned, err := findOrphanedWorktrees(c.storeRoot)
+	if err != nil {
+		return Result{
+			Status:  StatusWarning,
+			FixHint: fmt.Sprintf("Could not inspect object store at %s: %v", c.storeRoot, err),
+		}
+	}
+	c.orphanedCount = orphaned
+
+	if c.orphanedCount == 0 {
+		return Result{Status: StatusOK}
+	}
+	return Result{
+		Status:  StatusWarning,
+		FixHint: "Run 'gt doctor --fix' to prune orphaned worktrees from the shared object store",
+	}
+}
+
+// Fix runs ObjectStore.GC, which prunes worktree entries whose
+// directories no longer exist.
+func (c *PatrolObjectStoreCheck) Fix(ctx *CheckContext) error {
+	store := vcs.NewObjectStore(c.storeRoot)
+	return store.GC()
+}
+
+// findOrphanedWorktrees walks every bare repo under storeRoot and counts
+// workspaces whose path no longer exists on disk.
+func findOrphanedWorktrees(storeRoot string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(storeRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || filepath.Ext(path) != ".git" {
+			return nil
+		}
+		repo, err := vcs.NewWithGitDir(path, "", vcs.VCSGit)
+		if err != nil {
+			return filepath.SkipDir
+		}
+		workspaces, err := repo.WorkspaceList()
+		if err != nil {
+			return filepath.SkipDir
+		}
+		for _, ws := range workspaces {
+			if _, statErr := os.Stat(ws.Path); os.IsNotExist(statErr) {
+				count++
+			}
+		}
+		return filepath.SkipDir
+	})
+	return count, err
+}
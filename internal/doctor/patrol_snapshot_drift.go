@@ -0,0 +1,121 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/vcs"
+	"github.com/steveyegge/gastown/mayor/snapshot"
+)
+
+// RigDrift names a rig whose current HEAD no longer matches the most
+// recent town snapshot.
+type RigDrift struct {
+	Rig         string
+	SnapshotRev string
+	CurrentRev  string
+}
+
+// PatrolSnapshotDriftCheck compares the current town against its most
+// recent mayor/snapshots/*.json entry and reports rigs that have moved.
+type PatrolSnapshotDriftCheck struct {
+	latestSnapshotPath string
+	drifted            []RigDrift
+}
+
+// NewPatrolSnapshotDriftCheck creates the snapshot drift check.
+func NewPatrolSnapshotDriftCheck() *PatrolSnapshotDriftCheck {
+	return &PatrolSnapshotDriftCheck{}
+}
+
+// Name returns the check's identifier.
+func (c *PatrolSnapshotDriftCheck) Name() string {
+	return "patrol-snapshot-drift"
+}
+
+// CanFix reports that this check can restore the town to its snapshot.
+func (c *PatrolSnapshotDriftCheck) CanFix() bool {
+	return true
+}
+
+// Run compares every rig's HEAD against the most recent snapshot, if one
+// exists. A town with no snapshots yet is not a warning: snapshots are
+// opt-in.
+func (c *PatrolSnapshotDriftCheck) Run(ctx *CheckContext) Result {
+	c.drifted = nil
+
+	latest, err := latestSnapshotPath(ctx.TownRoot)
+	if err != nil || latest == "" {
+		return Result{Status: StatusOK}
+	}
+	c.latestSnapshotPath = latest
+
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return Result{Status: StatusOK}
+	}
+	var snap snapshot.TownSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Result{Status: StatusOK}
+	}
+
+	for name, rigSnap := range snap.Rigs {
+		rigDir := filepath.Join(ctx.TownRoot, name)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			continue
+		}
+		currentRev, err := repo.Rev("HEAD")
+		if err != nil {
+			continue
+		}
+		if currentRev != rigSnap.Rev {
+			c.drifted = append(c.drifted, RigDrift{
+				Rig:         name,
+				SnapshotRev: rigSnap.Rev,
+				CurrentRev:  currentRev,
+			})
+		}
+	}
+
+	if len(c.drifted) == 0 {
+		return Result{Status: StatusOK}
+	}
+	return Result{
+		Status:  StatusWarning,
+		FixHint: "Run 'gt doctor --fix' to restore rigs to their last snapshot",
+	}
+}
+
+// Fix restores the whole town from the snapshot Run compared against.
+func (c *PatrolSnapshotDriftCheck) Fix(ctx *CheckContext) error {
+	return snapshot.RestoreTownSnapshot(ctx.TownRoot, c.latestSnapshotPath)
+}
+
+// latestSnapshotPath returns the newest .json file under mayor/snapshots,
+// or "" if none exist. Snapshot filenames are timestamp-sortable, so the
+// lexically greatest name is the most recent.
+func latestSnapshotPath(townRoot string) (string, error) {
+	snapshotsDir := filepath.Join(townRoot, "mayor", "snapshots")
+	entries, err := os.ReadDir(snapshotsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return filepath.Join(snapshotsDir, names[len(names)-1]), nil
+}
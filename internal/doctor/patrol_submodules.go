@@ -0,0 +1,159 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/vcs"
+)
+
+// submoduleSentinelBranch marks a submodule as locally created rather than
+// tracked by the superproject, borrowing jiri's "submodule flag branch"
+// trick: a branch by this name checked out in the submodule means a rig
+// engineer added it by hand and drift/detached-HEAD warnings should be
+// suppressed for it.
+const submoduleSentinelBranch = "gastown-local-submodule"
+
+// SubmoduleIssue describes one problem found with a rig's submodule.
+type SubmoduleIssue struct {
+	Path   string
+	Reason string // "uninitialized", "detached", or "drifted"
+}
+
+// PatrolSubmodulesHealthyCheck verifies that every rig's git submodules
+// (or jj colocated subrepos) are initialized and checked out at the
+// commit the superproject pins.
+type PatrolSubmodulesHealthyCheck struct {
+	issuesByRig map[string][]SubmoduleIssue
+}
+
+// NewPatrolSubmodulesHealthyCheck creates the submodule health check.
+func NewPatrolSubmodulesHealthyCheck() *PatrolSubmodulesHealthyCheck {
+	return &PatrolSubmodulesHealthyCheck{}
+}
+
+// Name returns the check's identifier.
+func (c *PatrolSubmodulesHealthyCheck) Name() string {
+	return "patrol-submodules-healthy"
+}
+
+// CanFix reports that this check can repair what it finds.
+func (c *PatrolSubmodulesHealthyCheck) CanFix() bool {
+	return true
+}
+
+// Run enumerates rigs from mayor/rigs.json and flags any submodule that
+// is uninitialized, detached from its tracked branch, or drifted from
+// the superproject's pinned SHA.
+func (c *PatrolSubmodulesHealthyCheck) Run(ctx *CheckContext) Result {
+	c.issuesByRig = make(map[string][]SubmoduleIssue)
+
+	rigs, err := loadConfiguredRigNames(ctx.TownRoot)
+	if err != nil {
+		return Result{Status: StatusOK}
+	}
+
+	for _, rigName := range rigs {
+		rigDir := filepath.Join(ctx.TownRoot, rigName)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			continue
+		}
+
+		submodules, err := repo.SubmodulesList()
+		if err != nil || len(submodules) == 0 {
+			continue
+		}
+
+		for _, sm := range submodules {
+			status, err := repo.SubmoduleStatus(sm.Path)
+			if err != nil {
+				c.issuesByRig[rigName] = append(c.issuesByRig[rigName], SubmoduleIssue{
+					Path:   sm.Path,
+					Reason: "uninitialized",
+				})
+				continue
+			}
+			for _, reason := range classifySubmoduleStatus(status) {
+				c.issuesByRig[rigName] = append(c.issuesByRig[rigName], SubmoduleIssue{
+					Path:   sm.Path,
+					Reason: reason,
+				})
+			}
+		}
+	}
+
+	if len(c.issuesByRig) == 0 {
+		return Result{Status: StatusOK}
+	}
+	return Result{
+		Status:  StatusWarning,
+		FixHint: "Run 'gt doctor --fix' to init/update unhealthy submodules",
+	}
+}
+
+// Fix runs SubmoduleInit + SubmoduleUpdate for every flagged submodule.
+func (c *PatrolSubmodulesHealthyCheck) Fix(ctx *CheckContext) error {
+	for rigName, issues := range c.issuesByRig {
+		rigDir := filepath.Join(ctx.TownRoot, rigName)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			return fmt.Errorf("open rig %s: %w", rigName, err)
+		}
+
+		for _, issue := range issues {
+			if err := repo.SubmoduleInit(issue.Path); err != nil {
+				return fmt.Errorf("init submodule %s in %s: %w", issue.Path, rigName, err)
+			}
+			if err := repo.SubmoduleUpdate(issue.Path, true); err != nil {
+				return fmt.Errorf("update submodule %s in %s: %w", issue.Path, rigName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// classifySubmoduleStatus returns the issue reasons ("uninitialized",
+// "detached", "drifted") that apply to status, or nil if the submodule is
+// healthy. A submodule whose CurrentBranch is submoduleSentinelBranch was
+// created locally by a rig engineer rather than pinned by the
+// superproject, so detached/drift warnings are suppressed for it.
+func classifySubmoduleStatus(status vcs.SubmoduleStatus) []string {
+	if !status.Initialized {
+		return []string{"uninitialized"}
+	}
+	if status.CurrentBranch == submoduleSentinelBranch {
+		return nil
+	}
+
+	var reasons []string
+	if status.Detached {
+		reasons = append(reasons, "detached")
+	}
+	if status.Drifted {
+		reasons = append(reasons, "drifted")
+	}
+	return reasons
+}
+
+// loadConfiguredRigNames reads the configured rig names from mayor/rigs.json.
+func loadConfiguredRigNames(townRoot string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var rigsConfig config.RigsConfig
+	if err := json.Unmarshal(data, &rigsConfig); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	return names, nil
+}
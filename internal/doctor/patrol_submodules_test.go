@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/vcs"
+)
+
+func writeRigsConfig(t *testing.T, tmpDir string, rigNames []string) {
+	t.Helper()
+	mayorDir := filepath.Join(tmpDir, "mayor")
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		t.Fatalf("mkdir mayor: %v", err)
+	}
+
+	rigsConfig := config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	for _, name := range rigNames {
+		rigsConfig.Rigs[name] = config.RigEntry{}
+	}
+
+	data, err := json.Marshal(rigsConfig)
+	if err != nil {
+		t.Fatalf("marshal rigs.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorDir, "rigs.json"), data, 0644); err != nil {
+		t.Fatalf("write rigs.json: %v", err)
+	}
+}
+
+func TestNewPatrolSubmodulesHealthyCheck(t *testing.T) {
+	check := NewPatrolSubmodulesHealthyCheck()
+	if check == nil {
+		t.Fatal("NewPatrolSubmodulesHealthyCheck() returned nil")
+	}
+	if check.Name() != "patrol-submodules-healthy" {
+		t.Errorf("Name() = %q, want %q", check.Name(), "patrol-submodules-healthy")
+	}
+	if !check.CanFix() {
+		t.Error("CanFix() should return true")
+	}
+}
+
+func TestPatrolSubmodulesHealthyCheck_NoRigsConfigured(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	check := NewPatrolSubmodulesHealthyCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK (no rigs.json)", result.Status)
+	}
+	if len(check.issuesByRig) != 0 {
+		t.Errorf("issuesByRig count = %d, want 0", len(check.issuesByRig))
+	}
+}
+
+func TestPatrolSubmodulesHealthyCheck_RigNotARepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeRigsConfig(t, tmpDir, []string{"myproject"})
+	// myproject's directory never gets a .git/.jj, so vcs.New fails and
+	// the rig is skipped rather than flagged.
+	if err := os.MkdirAll(filepath.Join(tmpDir, "myproject"), 0755); err != nil {
+		t.Fatalf("mkdir myproject: %v", err)
+	}
+
+	check := NewPatrolSubmodulesHealthyCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK (rig isn't a repo)", result.Status)
+	}
+	if len(check.issuesByRig) != 0 {
+		t.Errorf("issuesByRig count = %d, want 0", len(check.issuesByRig))
+	}
+}
+
+func TestClassifySubmoduleStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status vcs.SubmoduleStatus
+		want   []string
+	}{
+		{
+			name:   "uninitialized",
+			status: vcs.SubmoduleStatus{Initialized: false},
+			want:   []string{"uninitialized"},
+		},
+		{
+			name:   "healthy",
+			status: vcs.SubmoduleStatus{Initialized: true},
+			want:   nil,
+		},
+		{
+			name:   "detached non-sentinel is flagged",
+			status: vcs.SubmoduleStatus{Initialized: true, Detached: true, CurrentBranch: ""},
+			want:   []string{"detached"},
+		},
+		{
+			name:   "detached sentinel branch is suppressed",
+			status: vcs.SubmoduleStatus{Initialized: true, Detached: true, CurrentBranch: submoduleSentinelBranch},
+			want:   nil,
+		},
+		{
+			name:   "drifted non-sentinel is flagged",
+			status: vcs.SubmoduleStatus{Initialized: true, Drifted: true},
+			want:   []string{"drifted"},
+		},
+		{
+			name:   "drifted sentinel branch is suppressed",
+			status: vcs.SubmoduleStatus{Initialized: true, Drifted: true, CurrentBranch: submoduleSentinelBranch},
+			want:   nil,
+		},
+		{
+			name:   "detached and drifted both flagged",
+			status: vcs.SubmoduleStatus{Initialized: true, Detached: true, Drifted: true},
+			want:   []string{"detached", "drifted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifySubmoduleStatus(tt.status)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classifySubmoduleStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
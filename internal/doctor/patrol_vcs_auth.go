@@ -0,0 +1,92 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/vcs"
+	"github.com/steveyegge/gastown/internal/vcs/auth"
+)
+
+// VcsAuthIssue names a rig whose remote has no discoverable credentials.
+type VcsAuthIssue struct {
+	Rig              string
+	Remote           string
+	Host             string
+	TriedButNotFound []auth.Source
+}
+
+// PatrolVcsAuthCheck proactively verifies that credentials exist for each
+// rig's "origin" remote, before a push/fetch fails mid-task.
+type PatrolVcsAuthCheck struct {
+	issues []VcsAuthIssue
+}
+
+// NewPatrolVcsAuthCheck creates the VCS credential check.
+func NewPatrolVcsAuthCheck() *PatrolVcsAuthCheck {
+	return &PatrolVcsAuthCheck{}
+}
+
+// Name returns the check's identifier.
+func (c *PatrolVcsAuthCheck) Name() string {
+	return "patrol-vcs-auth"
+}
+
+// CanFix reports true: Fix prints per-rig remediation steps. It does not
+// fetch or write credentials on the user's behalf.
+func (c *PatrolVcsAuthCheck) CanFix() bool {
+	return true
+}
+
+// Run checks every rig's origin remote against the same credential
+// sources auth.Discover uses, and flags rigs with none found.
+func (c *PatrolVcsAuthCheck) Run(ctx *CheckContext) Result {
+	c.issues = nil
+
+	rigs, err := loadConfiguredRigNames(ctx.TownRoot)
+	if err != nil {
+		return Result{Status: StatusOK}
+	}
+
+	for _, rigName := range rigs {
+		rigDir := filepath.Join(ctx.TownRoot, rigName)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			continue
+		}
+
+		remoteURL, err := repo.RemoteURL("origin")
+		if err != nil || remoteURL == "" {
+			continue
+		}
+
+		authErr := auth.Discover(remoteURL, nil, nil)
+		if len(authErr.Found) == 0 {
+			c.issues = append(c.issues, VcsAuthIssue{
+				Rig:              rigName,
+				Remote:           remoteURL,
+				Host:             authErr.Host,
+				TriedButNotFound: authErr.Tried,
+			})
+		}
+	}
+
+	if len(c.issues) == 0 {
+		return Result{Status: StatusOK}
+	}
+	return Result{
+		Status:  StatusWarning,
+		FixHint: "Run 'gt doctor --fix' for per-rig credential setup instructions",
+	}
+}
+
+// Fix does not touch credential stores (they're out of gastown's
+// control); it prints precise remediation for each flagged rig.
+func (c *PatrolVcsAuthCheck) Fix(ctx *CheckContext) error {
+	for _, issue := range c.issues {
+		fmt.Printf("%s (%s): no credentials found in %v\n", issue.Rig, issue.Remote, issue.TriedButNotFound)
+		fmt.Printf("  - add a \"machine %s\" entry to ~/.netrc, or\n", issue.Host)
+		fmt.Printf("  - run: git credential approve <<< $'protocol=https\\nhost=%s\\nusername=...\\npassword=...'\n", issue.Host)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewPatrolVcsAuthCheck(t *testing.T) {
+	check := NewPatrolVcsAuthCheck()
+	if check == nil {
+		t.Fatal("NewPatrolVcsAuthCheck() returned nil")
+	}
+	if check.Name() != "patrol-vcs-auth" {
+		t.Errorf("Name() = %q, want %q", check.Name(), "patrol-vcs-auth")
+	}
+	if !check.CanFix() {
+		t.Error("CanFix() should return true")
+	}
+}
+
+func TestPatrolVcsAuthCheck_NoRigsConfigured(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	check := NewPatrolVcsAuthCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK (no rigs.json)", result.Status)
+	}
+	if len(check.issues) != 0 {
+		t.Errorf("issues count = %d, want 0", len(check.issues))
+	}
+}
+
+func TestPatrolVcsAuthCheck_RigNotARepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeRigsConfig(t, tmpDir, []string{"myproject"})
+	if err := os.MkdirAll(filepath.Join(tmpDir, "myproject"), 0755); err != nil {
+		t.Fatalf("mkdir myproject: %v", err)
+	}
+
+	check := NewPatrolVcsAuthCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK (rig isn't a repo, skipped)", result.Status)
+	}
+}
@@ -0,0 +1,208 @@
+// Package auth discovers VCS credentials so that push/fetch/clone
+// failures can report which credential sources were tried, instead of
+// surfacing vcs.ErrAuthFailure with no actionable detail.
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// credentialFillTimeout bounds `git credential fill`: a misconfigured
+// credential helper (GUI prompt, SSH askpass) must degrade to "not
+// found" instead of hanging a doctor run that checks every rig.
+const credentialFillTimeout = 3 * time.Second
+
+// Source identifies a credential store that Discover inspected.
+type Source string
+
+const (
+	SourceNetrc          Source = "netrc"
+	SourceCookieFile     Source = "git-cookiefile"
+	SourceCredentialFill Source = "git-credential-fill"
+	SourceKeychain       Source = "keychain"
+)
+
+// Keyring looks up a secret in the OS keychain. It mirrors the
+// zalando/go-keyring Get(service, user) shape so a real per-platform
+// keychain can be plugged in without changing call sites.
+type Keyring interface {
+	Get(service, user string) (string, error)
+}
+
+// AuthError reports that a VCS operation against remote failed to
+// authenticate, and which credential sources were checked.
+type AuthError struct {
+	Remote string
+	Host   string
+	Tried  []Source
+	Found  []Source // subset of Tried that had a matching entry
+	Err    error    // underlying error from the failed git/jj invocation
+}
+
+func (e *AuthError) Error() string {
+	if len(e.Found) == 0 {
+		return fmt.Sprintf("authentication failed for %s: no credentials found (tried %s)", e.Remote, joinSources(e.Tried))
+	}
+	return fmt.Sprintf("authentication failed for %s: found credentials in %s but the remote still rejected them", e.Remote, joinSources(e.Found))
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+func joinSources(sources []Source) string {
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Discover scans netrc, git's cookie file, `git credential fill`, and the
+// OS keychain (if keyring is non-nil) for credentials matching remoteURL,
+// and wraps cause as an AuthError naming which sources were tried and
+// which had a match. The vcs package's Push, Fetch and Clone do not call
+// Discover themselves; a caller passes the failed invocation's error as
+// cause, or nil to check credentials up front.
+func Discover(remoteURL string, keyring Keyring, cause error) *AuthError {
+	host := hostOf(remoteURL)
+	err := &AuthError{Remote: remoteURL, Host: host, Err: cause}
+
+	err.Tried = append(err.Tried, SourceNetrc)
+	if hasNetrcEntry(host) {
+		err.Found = append(err.Found, SourceNetrc)
+	}
+
+	err.Tried = append(err.Tried, SourceCookieFile)
+	if hasCookieFileEntry(host) {
+		err.Found = append(err.Found, SourceCookieFile)
+	}
+
+	err.Tried = append(err.Tried, SourceCredentialFill)
+	if hasCredentialFill(remoteURL) {
+		err.Found = append(err.Found, SourceCredentialFill)
+	}
+
+	if keyring != nil {
+		err.Tried = append(err.Tried, SourceKeychain)
+		if _, kerr := keyring.Get("gastown", host); kerr == nil {
+			err.Found = append(err.Found, SourceKeychain)
+		}
+	}
+
+	return err
+}
+
+// hostOf returns the host component of a git/ssh/https remote URL.
+func hostOf(remoteURL string) string {
+	if u, err := url.Parse(remoteURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	// scp-like syntax: user@host:path
+	if at := strings.Index(remoteURL, "@"); at != -1 {
+		rest := remoteURL[at+1:]
+		if colon := strings.Index(rest, ":"); colon != -1 {
+			return rest[:colon]
+		}
+	}
+	return remoteURL
+}
+
+// hasNetrcEntry reports whether ~/.netrc has a "machine host" entry.
+func hasNetrcEntry(host string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	f, err := os.Open(filepath.Join(home, ".netrc"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "machine" && i+1 < len(fields) && fields[i+1] == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasCookieFileEntry reports whether git's configured http.cookiefile has
+// an entry covering host.
+func hasCookieFileEntry(host string) bool {
+	out, err := exec.Command("git", "config", "--get", "http.cookiefile").Output()
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSpace(string(out))
+	if path == "" {
+		return false
+	}
+	return hasCookieFileEntryAtPath(path, host)
+}
+
+// hasCookieFileEntryAtPath reports whether the Netscape-format cookie
+// file at path has an entry covering host, honoring the ".host" wildcard
+// rule used by site-wide cookies (e.g. .googlesource.com).
+func hasCookieFileEntryAtPath(path, host string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) == 0 {
+			continue
+		}
+		domain := fields[0]
+		if domain == host {
+			return true
+		}
+		if strings.HasPrefix(domain, ".") && strings.HasSuffix(host, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCredentialFill reports whether `git credential fill` returns a
+// username/password pair for remoteURL without prompting. It's bounded
+// by credentialFillTimeout so a credential helper that would otherwise
+// prompt interactively degrades to "not found" instead of hanging.
+func hasCredentialFill(remoteURL string) bool {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialFillTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\n\n", u.Scheme, u.Host))
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), "password=")
+}
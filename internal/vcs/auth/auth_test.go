@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHostOf(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/org/repo.git", "github.com"},
+		{"ssh://git@github.com/org/repo.git", "github.com"},
+		{"git@github.com:org/repo.git", "github.com"},
+		{"https://review.googlesource.com/a/repo", "review.googlesource.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := hostOf(tt.url); got != tt.want {
+				t.Errorf("hostOf(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasNetrcEntry(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	netrc := "machine github.com login alice password s3cr3t\nmachine example.com login bob password hunter2\n"
+	if err := os.WriteFile(filepath.Join(home, ".netrc"), []byte(netrc), 0600); err != nil {
+		t.Fatalf("write .netrc: %v", err)
+	}
+
+	if !hasNetrcEntry("github.com") {
+		t.Error("hasNetrcEntry(github.com) = false, want true")
+	}
+	if hasNetrcEntry("gitlab.com") {
+		t.Error("hasNetrcEntry(gitlab.com) = true, want false")
+	}
+}
+
+func TestHasNetrcEntry_NoFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if hasNetrcEntry("github.com") {
+		t.Error("hasNetrcEntry() = true with no ~/.netrc, want false")
+	}
+}
+
+func TestHasCookieFileEntry(t *testing.T) {
+	dir := t.TempDir()
+	cookiePath := filepath.Join(dir, "cookies.txt")
+	contents := "# Netscape HTTP Cookie File\n" +
+		".googlesource.com\tTRUE\t/\tTRUE\t2147483647\to\tgit-auth-token\n" +
+		"example.com\tFALSE\t/\tFALSE\t2147483647\tsession\tabc\n"
+	if err := os.WriteFile(cookiePath, []byte(contents), 0600); err != nil {
+		t.Fatalf("write cookiefile: %v", err)
+	}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"review.googlesource.com", true}, // matches the ".host" wildcard rule
+		{"googlesource.com", false},       // wildcard only covers subdomains
+		{"example.com", true},             // exact match
+		{"other.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := hasCookieFileEntryAtPath(cookiePath, tt.host); got != tt.want {
+				t.Errorf("hasCookieFileEntryAtPath(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthError_Error(t *testing.T) {
+	noneFound := &AuthError{Remote: "https://github.com/org/repo.git", Tried: []Source{SourceNetrc, SourceKeychain}}
+	if got := noneFound.Error(); got == "" {
+		t.Error("Error() returned empty string")
+	}
+
+	someFound := &AuthError{Remote: "https://github.com/org/repo.git", Tried: []Source{SourceNetrc}, Found: []Source{SourceNetrc}}
+	if got := someFound.Error(); got == "" {
+		t.Error("Error() returned empty string")
+	}
+}
+
+func TestAuthError_Unwrap(t *testing.T) {
+	cause := os.ErrPermission
+	err := &AuthError{Err: cause}
+	if err.Unwrap() != cause {
+		t.Error("Unwrap() did not return the wrapped cause")
+	}
+}
@@ -0,0 +1,169 @@
+package vcs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BranchStackStore persists the parent/child relationships recorded by
+// RecordDependency and answers the walks RestackBranchOnto needs.
+//
+// Git implementations root the store at <gitDir>/gastown/deps; jj
+// implementations root it alongside the op log. Each branch with a
+// recorded parent gets its own file named after the branch, holding a
+// newline-separated list of parent branches, most recent first.
+type BranchStackStore struct {
+	root string // directory holding one file per child branch
+}
+
+// NewBranchStackStore returns a store rooted at <gitDir>/gastown/deps.
+func NewBranchStackStore(gitDir string) *BranchStackStore {
+	return &BranchStackStore{root: filepath.Join(gitDir, "gastown", "deps")}
+}
+
+func (s *BranchStackStore) path(branch string) string {
+	return filepath.Join(s.root, branch)
+}
+
+// RecordDependency records that child is stacked on parent. If parent is
+// already the most recently recorded parent for child, this is a no-op.
+func (s *BranchStackStore) RecordDependency(child, parent string) error {
+	existing, err := s.Parents(child)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 && existing[0] == parent {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.root, 0o755); err != nil {
+		return fmt.Errorf("create deps dir: %w", err)
+	}
+	parents := append([]string{parent}, existing...)
+	contents := strings.Join(parents, "\n") + "\n"
+	if err := os.WriteFile(s.path(child), []byte(contents), 0o644); err != nil {
+		return fmt.Errorf("record dependency %s -> %s: %w", child, parent, err)
+	}
+	return nil
+}
+
+// Parents returns the recorded parents for branch, most recently recorded
+// first, or nil if branch has no recorded parent.
+func (s *BranchStackStore) Parents(branch string) ([]string, error) {
+	f, err := os.Open(s.path(branch))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read dependency file for %s: %w", branch, err)
+	}
+	defer f.Close()
+
+	var parents []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			parents = append(parents, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read dependency file for %s: %w", branch, err)
+	}
+	return parents, nil
+}
+
+// DependencyChain returns the chain of parent branches for branch,
+// ordered from its immediate parent up to the root (the branch with no
+// recorded parent). It returns an error if the recorded dependencies
+// contain a cycle.
+func (s *BranchStackStore) DependencyChain(branch string) ([]string, error) {
+	var chain []string
+	seen := map[string]bool{branch: true}
+
+	current := branch
+	for {
+		parents, err := s.Parents(current)
+		if err != nil {
+			return nil, err
+		}
+		if len(parents) == 0 {
+			return chain, nil
+		}
+		parent := parents[0]
+		if seen[parent] {
+			return nil, fmt.Errorf("dependency cycle detected at %s", parent)
+		}
+		seen[parent] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+}
+
+// Descendants returns the branches whose immediate (most recently
+// recorded) parent is branch.
+func (s *BranchStackStore) Descendants(branch string) ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read deps dir: %w", err)
+	}
+
+	var descendants []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		child := entry.Name()
+		parents, err := s.Parents(child)
+		if err != nil {
+			return nil, err
+		}
+		if len(parents) > 0 && parents[0] == branch {
+			descendants = append(descendants, child)
+		}
+	}
+	return descendants, nil
+}
+
+// rebaser is the subset of VCS that RestackBranchOnto needs, kept narrow
+// so it can be exercised with a small fake instead of a full VCS.
+type rebaser interface {
+	Checkout(ref string) error
+	Rebase(onto string) error
+	AbortRebase() error
+}
+
+// RestackBranchOnto rebases branch onto newBase, then walks store's
+// recorded descendants of branch and recursively restacks each onto
+// branch's new position in turn. If any rebase conflicts, the rebase is
+// aborted and RestackBranchOnto returns an error wrapping
+// ErrRebaseConflict with the name of the branch that failed; branches
+// already restacked before the failure are left rebased.
+func RestackBranchOnto(repo rebaser, store *BranchStackStore, branch, newBase string) error {
+	if err := repo.Checkout(branch); err != nil {
+		return fmt.Errorf("checkout %s: %w", branch, err)
+	}
+	if err := repo.Rebase(newBase); err != nil {
+		if abortErr := repo.AbortRebase(); abortErr != nil {
+			return fmt.Errorf("rebase %s onto %s: %w (also failed to abort: %v)", branch, newBase, ErrRebaseConflict, abortErr)
+		}
+		return fmt.Errorf("rebase %s onto %s: %w", branch, newBase, ErrRebaseConflict)
+	}
+
+	descendants, err := store.Descendants(branch)
+	if err != nil {
+		return fmt.Errorf("find descendants of %s: %w", branch, err)
+	}
+	for _, child := range descendants {
+		if err := RestackBranchOnto(repo, store, child, branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
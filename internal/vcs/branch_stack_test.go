@@ -0,0 +1,211 @@
+package vcs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBranchStackStore_RecordAndChain(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-a", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	chain, err := store.DependencyChain("feature-b")
+	if err != nil {
+		t.Fatalf("DependencyChain() error = %v", err)
+	}
+	want := []string{"feature-a", "main"}
+	if len(chain) != len(want) || chain[0] != want[0] || chain[1] != want[1] {
+		t.Errorf("DependencyChain() = %v, want %v", chain, want)
+	}
+}
+
+func TestBranchStackStore_DependencyChain_NoParent(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	chain, err := store.DependencyChain("main")
+	if err != nil {
+		t.Fatalf("DependencyChain() error = %v", err)
+	}
+	if len(chain) != 0 {
+		t.Errorf("DependencyChain() = %v, want empty", chain)
+	}
+}
+
+func TestBranchStackStore_RecordDependency_Idempotent(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() (repeat) error = %v", err)
+	}
+
+	parents, err := store.Parents("feature-b")
+	if err != nil {
+		t.Fatalf("Parents() error = %v", err)
+	}
+	if len(parents) != 1 {
+		t.Errorf("Parents() = %v, want single entry (no duplicate on re-record)", parents)
+	}
+}
+
+func TestBranchStackStore_RecordDependency_Rebased(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-b", "main"); err != nil {
+		t.Fatalf("RecordDependency() (rebased) error = %v", err)
+	}
+
+	parents, err := store.Parents("feature-b")
+	if err != nil {
+		t.Fatalf("Parents() error = %v", err)
+	}
+	want := []string{"main", "feature-a"}
+	if len(parents) != len(want) || parents[0] != want[0] || parents[1] != want[1] {
+		t.Errorf("Parents() = %v, want %v", parents, want)
+	}
+}
+
+func TestBranchStackStore_DependencyChain_Cycle(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	if err := store.RecordDependency("a", "b"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("b", "a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	if _, err := store.DependencyChain("a"); err == nil {
+		t.Error("DependencyChain() error = nil, want error for cycle")
+	}
+}
+
+func TestBranchStackStore_Descendants(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+
+	if err := store.RecordDependency("feature-a", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-b", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-c", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	descendants, err := store.Descendants("main")
+	if err != nil {
+		t.Fatalf("Descendants() error = %v", err)
+	}
+	if len(descendants) != 2 {
+		t.Errorf("Descendants(main) = %v, want 2 entries", descendants)
+	}
+
+	descendants, err = store.Descendants("feature-a")
+	if err != nil {
+		t.Fatalf("Descendants() error = %v", err)
+	}
+	if len(descendants) != 1 || descendants[0] != "feature-c" {
+		t.Errorf("Descendants(feature-a) = %v, want [feature-c]", descendants)
+	}
+}
+
+// fakeRebaser is a minimal rebaser for exercising RestackBranchOnto
+// without a real git/jj backend.
+type fakeRebaser struct {
+	current    string
+	conflictOn string // Rebase() fails when onto == conflictOn
+	rebased    []string
+	aborted    bool
+}
+
+func (f *fakeRebaser) Checkout(ref string) error {
+	f.current = ref
+	return nil
+}
+
+func (f *fakeRebaser) Rebase(onto string) error {
+	if onto == f.conflictOn {
+		return errors.New("conflict")
+	}
+	f.rebased = append(f.rebased, f.current+"->"+onto)
+	return nil
+}
+
+func (f *fakeRebaser) AbortRebase() error {
+	f.aborted = true
+	return nil
+}
+
+func TestRestackBranchOnto_WalksDescendants(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+	if err := store.RecordDependency("feature-a", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	repo := &fakeRebaser{}
+	if err := RestackBranchOnto(repo, store, "feature-a", "main"); err != nil {
+		t.Fatalf("RestackBranchOnto() error = %v", err)
+	}
+
+	want := []string{"feature-a->main", "feature-b->feature-a"}
+	if len(repo.rebased) != len(want) || repo.rebased[0] != want[0] || repo.rebased[1] != want[1] {
+		t.Errorf("rebased = %v, want %v", repo.rebased, want)
+	}
+}
+
+func TestRestackBranchOnto_ConflictAbortsAndWraps(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+	if err := store.RecordDependency("feature-a", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	repo := &fakeRebaser{conflictOn: "main"}
+	err := RestackBranchOnto(repo, store, "feature-a", "main")
+	if err == nil {
+		t.Fatal("RestackBranchOnto() error = nil, want conflict error")
+	}
+	if !errors.Is(err, ErrRebaseConflict) {
+		t.Errorf("RestackBranchOnto() error = %v, want wrapping ErrRebaseConflict", err)
+	}
+	if !repo.aborted {
+		t.Error("AbortRebase() was not called after conflict")
+	}
+}
+
+func TestRestackBranchOnto_DescendantConflictStopsWalk(t *testing.T) {
+	store := NewBranchStackStore(t.TempDir())
+	if err := store.RecordDependency("feature-a", "main"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+	if err := store.RecordDependency("feature-b", "feature-a"); err != nil {
+		t.Fatalf("RecordDependency() error = %v", err)
+	}
+
+	repo := &fakeRebaser{conflictOn: "feature-a"}
+	err := RestackBranchOnto(repo, store, "feature-a", "main")
+	if err == nil {
+		t.Fatal("RestackBranchOnto() error = nil, want conflict error")
+	}
+	if !errors.Is(err, ErrRebaseConflict) {
+		t.Errorf("RestackBranchOnto() error = %v, want wrapping ErrRebaseConflict", err)
+	}
+	want := []string{"feature-a->main"}
+	if len(repo.rebased) != len(want) || repo.rebased[0] != want[0] {
+		t.Errorf("rebased = %v, want %v (descendant should not have been restacked)", repo.rebased, want)
+	}
+}
@@ -0,0 +1,40 @@
+package vcs
+
+import "sync"
+
+// mergeProbeCache memoizes merge-conflict probes by resolved commit SHA
+// pair, so CheckConflictsBatch can be called repeatedly across a doctor
+// run or refinery queue cycle without re-probing a pair whose commits
+// haven't moved.
+//
+// GitVCS implementations should populate this via `git merge-tree
+// --write-tree --name-only` (git 2.38+), which computes conflicts
+// without touching the working tree, falling back to a worktree-based
+// probe on older git. JjVCS implementations should use `jj new -m probe
+// --no-edit` against a scratch workspace.
+type mergeProbeCache struct {
+	mu    sync.Mutex
+	byRev map[shaPair]ConflictResult
+}
+
+type shaPair struct {
+	source string
+	target string
+}
+
+func newMergeProbeCache() *mergeProbeCache {
+	return &mergeProbeCache{byRev: make(map[shaPair]ConflictResult)}
+}
+
+func (c *mergeProbeCache) get(sourceSHA, targetSHA string) (ConflictResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.byRev[shaPair{sourceSHA, targetSHA}]
+	return result, ok
+}
+
+func (c *mergeProbeCache) put(sourceSHA, targetSHA string, result ConflictResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byRev[shaPair{sourceSHA, targetSHA}] = result
+}
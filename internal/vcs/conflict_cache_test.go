@@ -0,0 +1,38 @@
+package vcs
+
+import "testing"
+
+func TestMergeProbeCache_GetMiss(t *testing.T) {
+	cache := newMergeProbeCache()
+
+	if _, ok := cache.get("sha1", "sha2"); ok {
+		t.Error("get() on empty cache returned ok=true, want false")
+	}
+}
+
+func TestMergeProbeCache_PutThenGet(t *testing.T) {
+	cache := newMergeProbeCache()
+	want := ConflictResult{Clean: false, Files: map[string]string{"a.go": "<<<<<<< conflict"}}
+
+	cache.put("sha1", "sha2", want)
+
+	got, ok := cache.get("sha1", "sha2")
+	if !ok {
+		t.Fatal("get() after put() returned ok=false, want true")
+	}
+	if got.Clean != want.Clean || got.Files["a.go"] != want.Files["a.go"] {
+		t.Errorf("get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeProbeCache_KeyedByBothSHAs(t *testing.T) {
+	cache := newMergeProbeCache()
+	cache.put("sha1", "sha2", ConflictResult{Clean: true})
+
+	if _, ok := cache.get("sha2", "sha1"); ok {
+		t.Error("get() matched a swapped (target, source) pair, want separate cache entries")
+	}
+	if _, ok := cache.get("sha1", "sha3"); ok {
+		t.Error("get() matched a different target SHA, want miss")
+	}
+}
@@ -34,7 +34,8 @@ func NewFromConfig(dir string, vcsType VCSType) (VCS, error) {
 
 // NewWithGitDir creates a VCS with explicit git/jj directory.
 // This is used for bare repos where gitDir points to the .git directory
-// and workDir may be empty or point to a worktree/workspace.
+// and workDir may be empty or point to a worktree/workspace. It is the
+// canonical construction path for rigs backed by a shared ObjectStore.
 func NewWithGitDir(gitDir, workDir string, vcsType VCSType) (VCS, error) {
 	switch vcsType {
 	case VCSJj:
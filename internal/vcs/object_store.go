@@ -0,0 +1,191 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ObjectStore is a single bare clone shared by every rig that points at
+// the same upstream, so fetch happens once per upstream instead of once
+// per rig. Each rig is then created as a git worktree against the shared
+// bare repo, via NewWithGitDir. The bare repo is always opened as git, so
+// no jj workspace is created.
+type ObjectStore struct {
+	// Root is the store's base directory, conventionally
+	// ~/.gastown/objects.
+	Root string
+}
+
+// NewObjectStore returns an ObjectStore rooted at root.
+func NewObjectStore(root string) *ObjectStore {
+	return &ObjectStore{Root: root}
+}
+
+// PathFor returns the bare-repo path remoteURL maps to within the store:
+// <root>/<host>/<path>.git.
+func (s *ObjectStore) PathFor(remoteURL string) (string, error) {
+	host, path, err := splitRemoteURL(remoteURL)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, host, path+".git"), nil
+}
+
+// splitRemoteURL extracts the host and repo path from a git remote URL,
+// handling both URL-form remotes (https://host/path, ssh://host/path)
+// and the SCP-like form `git clone` produces for SSH by default
+// (user@host:path).
+func splitRemoteURL(remoteURL string) (host, path string, err error) {
+	if u, parseErr := url.Parse(remoteURL); parseErr == nil && u.Host != "" {
+		host = u.Host
+		path = strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), ".git")
+		return host, path, nil
+	}
+
+	at := strings.Index(remoteURL, "@")
+	colon := strings.Index(remoteURL, ":")
+	if at == -1 || colon == -1 || colon < at {
+		return "", "", fmt.Errorf("parse remote url %q: not a recognized git URL", remoteURL)
+	}
+	host = remoteURL[at+1 : colon]
+	path = strings.TrimSuffix(remoteURL[colon+1:], ".git")
+	return host, path, nil
+}
+
+// Ensure returns the VCS for the bare clone of remoteURL under the
+// store, bare-cloning it first if it doesn't exist yet.
+func (s *ObjectStore) Ensure(remoteURL string) (VCS, error) {
+	barePath, err := s.PathFor(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(barePath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(barePath), 0755); err != nil {
+			return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(barePath), err)
+		}
+		repo, err := NewWithGitDir(barePath, "", VCSGit)
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.CloneBare(remoteURL, barePath); err != nil {
+			return nil, fmt.Errorf("clone bare %s: %w", remoteURL, err)
+		}
+		return repo, nil
+	}
+	return NewWithGitDir(barePath, "", VCSGit)
+}
+
+// AddWorktree creates workDir as a git worktree of the shared bare clone
+// for remoteURL, checked out on branch. It does not create jj workspaces.
+func (s *ObjectStore) AddWorktree(remoteURL, workDir, branch string) error {
+	bare, err := s.Ensure(remoteURL)
+	if err != nil {
+		return err
+	}
+	return bare.WorkspaceAdd(workDir, branch)
+}
+
+// GC prunes worktree entries whose directories no longer exist, across
+// every bare repo under the store. A repo that can't be opened or pruned
+// is recorded and skipped rather than aborting the rest of the store;
+// GC returns a combined error (via errors.Join) if any repo failed.
+func (s *ObjectStore) GC() error {
+	var errs []error
+	walkErr := filepath.WalkDir(s.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if !d.IsDir() || !strings.HasSuffix(path, ".git") {
+			return nil
+		}
+		repo, err := NewWithGitDir(path, "", VCSGit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("open %s: %w", path, err))
+			return fs.SkipDir
+		}
+		if err := repo.WorkspacePrune(); err != nil {
+			errs = append(errs, fmt.Errorf("prune %s: %w", path, err))
+		}
+		return fs.SkipDir
+	})
+	if walkErr != nil {
+		errs = append(errs, walkErr)
+	}
+	return errors.Join(errs...)
+}
+
+// MigrateToSharedStore converts an existing standalone clone at rigDir
+// into a worktree of the shared bare repo for its origin remote, without
+// losing local branches: it fetches every local branch from rigDir into
+// the bare repo before re-pointing rigDir at it as a worktree. The fetch
+// pulls from rigDir itself (not from origin), so commits that were made
+// locally but never pushed survive the migration, on any branch, not
+// just the one currently checked out.
+//
+// FetchBranch(rigDir, b) is only used to land the branch's objects in
+// the bare store; a bare `git fetch <path> <branch>` with no refspec
+// only updates FETCH_HEAD; it doesn't reliably create or move a local
+// branch ref named b. So once the fetch has landed the objects, the
+// branch ref itself is pinned explicitly to the SHA resolved in the
+// source clone, rather than trusting FetchBranch to have named it.
+func (s *ObjectStore) MigrateToSharedStore(rigDir string) error {
+	repo, err := New(rigDir)
+	if err != nil {
+		return err
+	}
+	remoteURL, err := repo.RemoteURL("origin")
+	if err != nil {
+		return fmt.Errorf("remote url for %s: %w", rigDir, err)
+	}
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("current branch for %s: %w", rigDir, err)
+	}
+	localBranches, err := repo.ListBranches("")
+	if err != nil {
+		return fmt.Errorf("list branches for %s: %w", rigDir, err)
+	}
+
+	bare, err := s.Ensure(remoteURL)
+	if err != nil {
+		return err
+	}
+	for _, b := range localBranches {
+		sha, err := repo.Rev(b)
+		if err != nil {
+			return fmt.Errorf("resolve %s in %s: %w", b, rigDir, err)
+		}
+		if err := bare.FetchBranch(rigDir, b); err != nil {
+			return fmt.Errorf("fetch %s into shared store: %w", b, err)
+		}
+		exists, err := bare.BranchExists(b)
+		if err != nil {
+			return fmt.Errorf("check %s in shared store: %w", b, err)
+		}
+		if exists {
+			if err := bare.ResetBranch(b, sha); err != nil {
+				return fmt.Errorf("pin %s to %s in shared store: %w", b, sha, err)
+			}
+		} else if err := bare.CreateBranchFrom(b, sha); err != nil {
+			return fmt.Errorf("create %s at %s in shared store: %w", b, sha, err)
+		}
+	}
+
+	tmpDir := rigDir + ".gastown-migrate"
+	if err := os.Rename(rigDir, tmpDir); err != nil {
+		return fmt.Errorf("move aside %s: %w", rigDir, err)
+	}
+	if err := bare.WorkspaceAddExisting(rigDir, branch); err != nil {
+		if restoreErr := os.Rename(tmpDir, rigDir); restoreErr != nil {
+			return fmt.Errorf("add worktree at %s: %w (also failed to restore original clone from %s: %v)", rigDir, err, tmpDir, restoreErr)
+		}
+		return fmt.Errorf("add worktree at %s: %w", rigDir, err)
+	}
+	return os.RemoveAll(tmpDir)
+}
@@ -0,0 +1,50 @@
+package vcs
+
+import "testing"
+
+func TestSplitRemoteURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantHost string
+		wantPath string
+	}{
+		{"https://github.com/org/repo.git", "github.com", "org/repo"},
+		{"https://github.com/org/repo", "github.com", "org/repo"},
+		{"ssh://git@github.com/org/repo.git", "github.com", "org/repo"},
+		{"git@github.com:org/repo.git", "github.com", "org/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, path, err := splitRemoteURL(tt.url)
+			if err != nil {
+				t.Fatalf("splitRemoteURL(%q) error = %v", tt.url, err)
+			}
+			if host != tt.wantHost {
+				t.Errorf("host = %q, want %q", host, tt.wantHost)
+			}
+			if path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestSplitRemoteURL_Unrecognized(t *testing.T) {
+	if _, _, err := splitRemoteURL("not a url"); err == nil {
+		t.Error("splitRemoteURL() error = nil, want error for unrecognized URL")
+	}
+}
+
+func TestObjectStore_PathFor(t *testing.T) {
+	store := NewObjectStore("/home/user/.gastown/objects")
+
+	got, err := store.PathFor("git@github.com:org/repo.git")
+	if err != nil {
+		t.Fatalf("PathFor() error = %v", err)
+	}
+	want := "/home/user/.gastown/objects/github.com/org/repo.git"
+	if got != want {
+		t.Errorf("PathFor() = %q, want %q", got, want)
+	}
+}
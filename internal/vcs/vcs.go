@@ -23,7 +23,12 @@ var (
 	ErrNotARepo       = errors.New("not a repository")
 	ErrMergeConflict  = errors.New("merge conflict")
 	ErrRebaseConflict = errors.New("rebase conflict")
-	ErrAuthFailure    = errors.New("authentication failed")
+	// ErrAuthFailure is returned by Push, Fetch, and Clone when the
+	// underlying git/jj invocation fails for auth reasons. Callers that
+	// need to know which credential sources were checked should use
+	// vcs/auth.Discover to get a vcs/auth.AuthError instead of matching
+	// on this sentinel alone.
+	ErrAuthFailure = errors.New("authentication failed")
 )
 
 // Status represents the working directory state.
@@ -42,6 +47,53 @@ type Workspace struct {
 	Commit string // Commit SHA (git) or commit ID (jj)
 }
 
+// Submodule describes a single entry from .gitmodules: a nested
+// repository pinned to a specific commit.
+type Submodule struct {
+	Path   string // Path to the submodule, relative to the superproject root.
+	URL    string // Configured remote URL.
+	Branch string // Tracking branch, if .gitmodules pins one ("" otherwise).
+	SHA    string // Commit the superproject has pinned this submodule to.
+}
+
+// SubmoduleStatus reports whether a submodule's working copy matches
+// what the superproject has pinned.
+type SubmoduleStatus struct {
+	Submodule
+	Initialized   bool   // false if the submodule has never been checked out.
+	Detached      bool   // true if the submodule's HEAD isn't on Branch.
+	Drifted       bool   // true if the checked-out commit differs from SHA.
+	CurrentSHA    string // Commit the submodule is actually checked out at.
+	CurrentBranch string // Branch the submodule's HEAD is on, if any ("" when HEAD is on no branch).
+}
+
+// Snapshot pins a single rig to a specific revision, mirroring jiri's
+// manifest+snapshot model so a town's state can be reproduced for CI or
+// bisect workflows. See mayor/snapshot for the town-wide save/restore.
+type Snapshot struct {
+	URL        string
+	Branch     string
+	Rev        string
+	VCSType    VCSType
+	Workspaces []Workspace
+}
+
+// ConflictPair names a candidate merge for CheckConflictsBatch: would
+// source merge cleanly into target?
+type ConflictPair struct {
+	Source string
+	Target string
+}
+
+// ConflictResult is the outcome of probing one ConflictPair.
+type ConflictResult struct {
+	Clean bool
+	// Files maps each conflicting path to its conflict markers, so
+	// callers (e.g. the refinery engineer) can surface rich diagnostics
+	// instead of just a file list.
+	Files map[string]string
+}
+
 // UncommittedWork contains information about uncommitted changes.
 type UncommittedWork struct {
 	HasChanges      bool
@@ -177,6 +229,37 @@ type VCS interface {
 	// The merge is aborted after checking - no changes are made.
 	CheckConflicts(source, target string) ([]string, error)
 
+	// CheckConflictsBatch probes every pair in one call, reusing a
+	// merge-probe cache keyed by (sourceSHA, targetSHA) so repeated pairs
+	// across a doctor run or queue cycle aren't re-probed. Results are
+	// keyed by the ConflictPair as given, not by resolved SHA.
+	CheckConflictsBatch(pairs []ConflictPair) (map[ConflictPair]ConflictResult, error)
+
+	// === Branch Stacking ===
+
+	// RecordDependency records that child branch is stacked on parent,
+	// so RestackOnto knows to carry child along when parent moves.
+	// Git stores this under .git/gastown/deps/<child> as a newline-
+	// separated list of parent branches; jj stores the equivalent in a
+	// bookmark-prefixed file alongside the op log. GitVCS and JjVCS
+	// implementations should hold a BranchStackStore rooted at their
+	// respective directory and delegate to it.
+	RecordDependency(child, parent string) error
+
+	// DependencyChain returns the chain of parent branches for branch,
+	// ordered from its immediate parent up to the root (the branch that
+	// has no recorded parent, typically the default branch). Delegates
+	// to BranchStackStore.DependencyChain.
+	DependencyChain(branch string) ([]string, error)
+
+	// RestackOnto rebases branch onto newBase, then walks the recorded
+	// dependency chain and rebases each descendant onto its (now-moved)
+	// parent in order. If a descendant's rebase conflicts, the rebase is
+	// aborted via AbortRebase and RestackOnto returns ErrRebaseConflict
+	// wrapping the name of the branch that failed. Delegates to
+	// RestackBranchOnto.
+	RestackOnto(branch, newBase string) error
+
 	// === Workspaces (git worktrees / jj workspaces) ===
 
 	// WorkspaceAdd creates a new workspace with a new branch.
@@ -224,4 +307,25 @@ type VCS interface {
 
 	// UnpushedCommits returns number of commits not pushed to upstream.
 	UnpushedCommits() (int, error)
+
+	// === Submodules ===
+
+	// SubmodulesList returns the submodules declared in the repository.
+	// For jj, this reads .gitmodules from the colocated .git directory.
+	SubmodulesList() ([]Submodule, error)
+
+	// SubmoduleInit initializes the submodule at path (registers it for
+	// update without populating it).
+	SubmoduleInit(path string) error
+
+	// SubmoduleUpdate checks out the pinned SHA for the submodule at path.
+	// If recursive is true, nested submodules are updated too.
+	SubmoduleUpdate(path string, recursive bool) error
+
+	// SubmoduleStatus reports the sync state of the submodule at path.
+	SubmoduleStatus(path string) (SubmoduleStatus, error)
+
+	// SubmoduleSync rewrites submodule remote URLs from .gitmodules and,
+	// for jj, keeps the working-copy change in sync with the result.
+	SubmoduleSync() error
 }
@@ -0,0 +1,182 @@
+// Package snapshot saves and restores the revision state of every rig in
+// a town, mirroring jiri's manifest+snapshot model so a town can be
+// reproduced exactly for CI or bisect workflows.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/vcs"
+)
+
+// schemaVersion is bumped whenever the TownSnapshot JSON shape changes
+// in a way RestoreTownSnapshot needs to know about.
+const schemaVersion = 1
+
+// ToolVersion is the gastown version stamped into saved snapshots. Set
+// at build time via -ldflags; defaults to "dev" for local builds.
+var ToolVersion = "dev"
+
+// TownSnapshot pins every rig in a town to a specific revision.
+type TownSnapshot struct {
+	SchemaVersion int                     `json:"schemaVersion"`
+	ToolVersion   string                  `json:"toolVersion"`
+	CreatedAt     time.Time               `json:"createdAt"`
+	Rigs          map[string]vcs.Snapshot `json:"rigs"`
+}
+
+// SaveTownSnapshot walks every rig in mayor/rigs.json, records its
+// current URL, branch, revision, and workspaces, and writes the result
+// to mayor/snapshots/<timestamp>.json under root.
+func SaveTownSnapshot(root string) (*TownSnapshot, error) {
+	rigNames, err := loadRigNames(root)
+	if err != nil {
+		return nil, fmt.Errorf("load rigs.json: %w", err)
+	}
+
+	snap := &TownSnapshot{
+		SchemaVersion: schemaVersion,
+		ToolVersion:   ToolVersion,
+		CreatedAt:     time.Now(),
+		Rigs:          make(map[string]vcs.Snapshot, len(rigNames)),
+	}
+
+	for _, name := range rigNames {
+		rigDir := filepath.Join(root, name)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			return nil, fmt.Errorf("open rig %s: %w", name, err)
+		}
+
+		rev, err := repo.Rev("HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("rev HEAD for rig %s: %w", name, err)
+		}
+		remoteURL, err := repo.RemoteURL("origin")
+		if err != nil {
+			return nil, fmt.Errorf("remote url for rig %s: %w", name, err)
+		}
+		branch, err := repo.CurrentBranch()
+		if err != nil {
+			return nil, fmt.Errorf("current branch for rig %s: %w", name, err)
+		}
+		workspaces, err := repo.WorkspaceList()
+		if err != nil {
+			return nil, fmt.Errorf("workspace list for rig %s: %w", name, err)
+		}
+
+		snap.Rigs[name] = vcs.Snapshot{
+			URL:        remoteURL,
+			Branch:     branch,
+			Rev:        rev,
+			VCSType:    repo.Type(),
+			Workspaces: workspaces,
+		}
+	}
+
+	snapshotsDir := filepath.Join(root, "mayor", "snapshots")
+	if err := os.MkdirAll(snapshotsDir, 0755); err != nil {
+		return nil, fmt.Errorf("mkdir %s: %w", snapshotsDir, err)
+	}
+	path := filepath.Join(snapshotsDir, snap.CreatedAt.UTC().Format("20060102-150405")+".json")
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return snap, nil
+}
+
+// RestoreTownSnapshot reads a TownSnapshot from path and brings every
+// recorded rig back to its pinned revision, cloning any rig that is
+// missing from root and re-creating any recorded workspaces.
+func RestoreTownSnapshot(root, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var snap TownSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+	if snap.SchemaVersion != schemaVersion {
+		return fmt.Errorf("snapshot %s has schema version %d, want %d", path, snap.SchemaVersion, schemaVersion)
+	}
+
+	for name, rigSnap := range snap.Rigs {
+		rigDir := filepath.Join(root, name)
+		repo, err := vcs.New(rigDir)
+		if err != nil {
+			repo, err = vcs.NewFromConfig(rigDir, rigSnap.VCSType)
+			if err != nil {
+				return fmt.Errorf("init rig %s: %w", name, err)
+			}
+			if err := repo.Clone(rigSnap.URL, rigDir); err != nil {
+				return fmt.Errorf("clone rig %s: %w", name, err)
+			}
+		}
+
+		if err := repo.Fetch("origin"); err != nil {
+			return fmt.Errorf("fetch rig %s: %w", name, err)
+		}
+		if err := repo.Checkout(rigSnap.Rev); err != nil {
+			return fmt.Errorf("checkout %s for rig %s: %w", rigSnap.Rev, name, err)
+		}
+
+		existing, err := repo.WorkspaceList()
+		if err != nil {
+			return fmt.Errorf("list workspaces for rig %s: %w", name, err)
+		}
+		for _, ws := range rigSnap.Workspaces {
+			if workspaceExists(existing, ws) {
+				continue
+			}
+			if err := repo.WorkspaceAddExisting(ws.Path, ws.Branch); err != nil {
+				return fmt.Errorf("restore workspace %s for rig %s: %w", ws.Path, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// workspaceExists reports whether want is already present in existing,
+// so RestoreTownSnapshot can be re-run against a town that's already at
+// (or partway to) the snapshot without erroring on workspaces that were
+// already recreated.
+func workspaceExists(existing []vcs.Workspace, want vcs.Workspace) bool {
+	for _, ws := range existing {
+		if ws.Path == want.Path && ws.Branch == want.Branch {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRigNames reads the configured rig names from mayor/rigs.json.
+func loadRigNames(root string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var rigsConfig config.RigsConfig
+	if err := json.Unmarshal(data, &rigsConfig); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	return names, nil
+}
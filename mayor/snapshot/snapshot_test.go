@@ -0,0 +1,118 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/vcs"
+)
+
+func TestWorkspaceExists(t *testing.T) {
+	existing := []vcs.Workspace{
+		{Path: "/rigs/myproject/wt1", Branch: "feature-a", Commit: "abc123"},
+	}
+
+	tests := []struct {
+		name string
+		want vcs.Workspace
+		ok   bool
+	}{
+		{"same path and branch", vcs.Workspace{Path: "/rigs/myproject/wt1", Branch: "feature-a"}, true},
+		{"same path, different branch", vcs.Workspace{Path: "/rigs/myproject/wt1", Branch: "feature-b"}, false},
+		{"different path", vcs.Workspace{Path: "/rigs/myproject/wt2", Branch: "feature-a"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workspaceExists(existing, tt.want); got != tt.ok {
+				t.Errorf("workspaceExists() = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTownSnapshotRoundTrip(t *testing.T) {
+	snap := TownSnapshot{
+		SchemaVersion: schemaVersion,
+		ToolVersion:   "test",
+		CreatedAt:     time.Unix(1700000000, 0).UTC(),
+		Rigs: map[string]vcs.Snapshot{
+			"myproject": {
+				URL:     "https://example.com/org/myproject.git",
+				Branch:  "main",
+				Rev:     "deadbeef",
+				VCSType: vcs.VCSGit,
+				Workspaces: []vcs.Workspace{
+					{Path: "/rigs/myproject/wt1", Branch: "feature-a", Commit: "abc123"},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got TownSnapshot
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got.SchemaVersion != snap.SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", got.SchemaVersion, snap.SchemaVersion)
+	}
+	if !got.CreatedAt.Equal(snap.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, snap.CreatedAt)
+	}
+	rigSnap, ok := got.Rigs["myproject"]
+	if !ok {
+		t.Fatal("Rigs[\"myproject\"] missing after round trip")
+	}
+	if rigSnap.Rev != "deadbeef" {
+		t.Errorf("Rev = %q, want %q", rigSnap.Rev, "deadbeef")
+	}
+	if len(rigSnap.Workspaces) != 1 || rigSnap.Workspaces[0].Path != "/rigs/myproject/wt1" {
+		t.Errorf("Workspaces = %+v, want one workspace at /rigs/myproject/wt1", rigSnap.Workspaces)
+	}
+}
+
+func TestLoadRigNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	mayorDir := filepath.Join(tmpDir, "mayor")
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		t.Fatalf("mkdir mayor: %v", err)
+	}
+
+	rigsConfig := config.RigsConfig{Rigs: map[string]config.RigEntry{
+		"myproject": {},
+		"other":     {},
+	}}
+	data, err := json.Marshal(rigsConfig)
+	if err != nil {
+		t.Fatalf("marshal rigs.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorDir, "rigs.json"), data, 0644); err != nil {
+		t.Fatalf("write rigs.json: %v", err)
+	}
+
+	names, err := loadRigNames(tmpDir)
+	if err != nil {
+		t.Fatalf("loadRigNames() error = %v", err)
+	}
+	if len(names) != 2 {
+		t.Errorf("loadRigNames() returned %d names, want 2", len(names))
+	}
+}
+
+func TestLoadRigNames_NoConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if _, err := loadRigNames(tmpDir); err == nil {
+		t.Error("loadRigNames() error = nil, want error (no rigs.json)")
+	}
+}